@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	awsCredentials "github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -21,7 +22,16 @@ import (
 	"github.com/hashicorp/go-multierror"
 )
 
-func GetAccountInfo(iamconn *iam.IAM, stsconn *sts.STS, authProviderName string) (string, string, error) {
+func GetAccountInfo(c *Config, iamconn *iam.IAM, stsconn *sts.STS, authProviderName string) (string, string, error) {
+	if c.SkipCredentialsValidation {
+		log.Println("[INFO] skip_credentials_validation set, not calling STS/IAM to validate credentials")
+		return c.DomainID, c.AccountID, nil
+	}
+	if c.SkipRequestingAccountID {
+		log.Println("[INFO] skip_requesting_account_id set, using statically configured domain/account ID")
+		return c.DomainID, c.AccountID, nil
+	}
+
 	var errors error
 	// If we have creds from instance profile, we can use metadata API
 	if authProviderName == ec2rolecreds.ProviderName {
@@ -114,12 +124,45 @@ func GetCredentials(c *Config) (*awsCredentials.Credentials, error) {
 			SecretAccessKey: c.SecretKey,
 			SessionToken:    c.SecurityToken,
 		}},
+	}
+
+	// A cloud explicitly selected via cloud_name/OS_CLOUD is a deliberate
+	// choice of identity, so it must win over ambient AWS_* env vars and the
+	// default shared credentials file, not be silently overridden by them.
+	if cloudName := resolveCloudName(c); cloudName != "" {
+		cloud, err := loadCloudAuth(cloudName)
+		if err != nil {
+			log.Printf("[DEBUG] Could not load cloud %q from clouds.yaml: %s", cloudName, err)
+		} else {
+			log.Printf("[INFO] Loaded credentials for cloud %q from clouds.yaml", cloudName)
+			providers = append(providers, &awsCredentials.StaticProvider{Value: awsCredentials.Value{
+				AccessKeyID:     cloud.AccessKey,
+				SecretAccessKey: cloud.SecretKey,
+				ProviderName:    "SharedConfigCredentials: clouds.yaml",
+			}})
+
+			// Fill in Region/DomainID/AccountID from the cloud entry when the
+			// Terraform configuration left them unset, mirroring how the
+			// OpenStack CLI treats clouds.yaml as a source for more than auth.
+			if c.Region == "" {
+				c.Region = cloud.Region
+			}
+			if c.DomainID == "" {
+				c.DomainID = cloud.DomainID
+			}
+			if c.AccountID == "" {
+				c.AccountID = cloud.ProjectID
+			}
+		}
+	}
+
+	providers = append(providers,
 		&awsCredentials.EnvProvider{},
 		&awsCredentials.SharedCredentialsProvider{
-			Filename: "",
-			Profile:  "",
+			Filename: resolveSharedCredentialsFile(c),
+			Profile:  resolveProfile(c),
 		},
-	}
+	)
 
 	// Build isolated HTTP client to avoid issues with globally-shared settings
 	client := cleanhttp.DefaultClient()
@@ -154,25 +197,155 @@ func GetCredentials(c *Config) (*awsCredentials.Credentials, error) {
 		log.Print("[INFO] ECS container credentials detected, RemoteCredProvider added to auth chain")
 	}
 
-	// Real AWS should reply to a simple metadata request.
-	// We check it actually does to ensure something else didn't just
-	// happen to be listening on the same IP:Port
-	metadataClient := ec2metadata.New(session.New(cfg))
-	if metadataClient.Available() {
-		providers = append(providers, &ec2rolecreds.EC2RoleProvider{
-			Client: metadataClient,
-		})
-		log.Print("[INFO] AWS EC2 instance detected via default metadata" +
-			" API endpoint, EC2RoleProvider added to the auth chain")
+	// Add a web identity / OIDC provider for CI federation (EKS IRSA,
+	// GitHub Actions OIDC, ...) when a token file and role are configured.
+	if tokenFile := resolveWebIdentityTokenFile(c); tokenFile != "" {
+		if roleARN := resolveWebIdentityRoleARN(c); roleARN != "" {
+			sess, err := session.NewSession(stsConfig(c.Region))
+			if err != nil {
+				return nil, errwrap.Wrapf("Error creating AWS session: {{err}}", err)
+			}
+			providers = append(providers, stscreds.NewWebIdentityRoleProvider(
+				sts.New(sess), roleARN, resolveWebIdentityRoleSessionName(c), tokenFile))
+			log.Printf("[INFO] web_identity_token_file configured, WebIdentityRoleProvider added"+
+				" to the auth chain for role %q", roleARN)
+		} else {
+			log.Print("[WARN] web_identity_token_file is set but role_arn is not, ignoring")
+		}
+	}
+
+	if c.SkipMetadataApiCheck {
+		log.Print("[INFO] skip_metadata_api_check set, not adding EC2RoleProvider to the auth chain")
 	} else {
-		if usedEndpoint == "" {
-			usedEndpoint = "default location"
+		// Real AWS should reply to a simple metadata request.
+		// We check it actually does to ensure something else didn't just
+		// happen to be listening on the same IP:Port
+		metadataClient := ec2metadata.New(session.New(cfg))
+		if metadataClient.Available() {
+			providers = append(providers, &ec2rolecreds.EC2RoleProvider{
+				Client: metadataClient,
+			})
+			log.Print("[INFO] AWS EC2 instance detected via default metadata" +
+				" API endpoint, EC2RoleProvider added to the auth chain")
+		} else {
+			if usedEndpoint == "" {
+				usedEndpoint = "default location"
+			}
+			log.Printf("[INFO] Ignoring AWS metadata API endpoint at %s "+
+				"as it doesn't return any instance-id", usedEndpoint)
+		}
+	}
+
+	chainCreds := awsCredentials.NewChainCredentials(providers)
+	if c.AssumeRoleARN == "" {
+		return chainCreds, nil
+	}
+
+	log.Printf("[INFO] assume_role configured, wrapping credentials for role %q", c.AssumeRoleARN)
+	assumeRoleCfg := stsConfig(c.Region)
+	assumeRoleCfg.Credentials = chainCreds
+	sess, err := session.NewSession(assumeRoleCfg)
+	if err != nil {
+		return nil, errwrap.Wrapf("Error creating AWS session: {{err}}", err)
+	}
+
+	return assumeRoleCredentials(c, sts.New(sess)), nil
+}
+
+// stsConfig builds an aws.Config for talking to STS. STS calls are real
+// network round-trips, unlike the EC2 metadata probe, so they must not
+// inherit GetCredentials' aggressive metadata-probe timeout, and STS is an
+// unrelated service to the EC2 metadata endpoint set via AWS_METADATA_URL,
+// so it gets its own client and no endpoint override. Region is required:
+// without it the SDK signer fails AssumeRole/AssumeRoleWithWebIdentity calls
+// with MissingRegion.
+func stsConfig(region string) *aws.Config {
+	return &aws.Config{
+		HTTPClient: cleanhttp.DefaultClient(),
+		Region:     aws.String(region),
+	}
+}
+
+// assumeRoleCredentials wraps creds obtained from the base provider chain
+// with an STS AssumeRole provider, so downstream clients authenticate as
+// the configured role rather than the caller's own identity. stsClient is
+// accepted as an interface so tests can inject a fake STS backend.
+func assumeRoleCredentials(c *Config, stsClient stscreds.AssumeRoler) *awsCredentials.Credentials {
+	return stscreds.NewCredentialsWithClient(stsClient, c.AssumeRoleARN, func(p *stscreds.AssumeRoleProvider) {
+		if c.AssumeRoleSessionName != "" {
+			p.RoleSessionName = c.AssumeRoleSessionName
 		}
-		log.Printf("[INFO] Ignoring AWS metadata API endpoint at %s "+
-			"as it doesn't return any instance-id", usedEndpoint)
+		if c.AssumeRoleExternalID != "" {
+			p.ExternalID = aws.String(c.AssumeRoleExternalID)
+		}
+		if c.AssumeRolePolicy != "" {
+			p.Policy = aws.String(c.AssumeRolePolicy)
+		}
+	})
+}
+
+// resolveProfile returns the shared-credentials profile to use, preferring
+// the Terraform-configured value over AWS_PROFILE and finally the
+// FlexibleEngine-native OS_PROFILE.
+func resolveProfile(c *Config) string {
+	if c.Profile != "" {
+		return c.Profile
+	}
+	if v := os.Getenv("AWS_PROFILE"); v != "" {
+		return v
+	}
+	return os.Getenv("OS_PROFILE")
+}
+
+// resolveSharedCredentialsFile returns the shared credentials file path to
+// use, preferring the Terraform-configured value over AWS_SHARED_CREDENTIALS_FILE.
+// An empty result lets SharedCredentialsProvider fall back to its own default.
+func resolveSharedCredentialsFile(c *Config) string {
+	if c.SharedCredentialsFile != "" {
+		return c.SharedCredentialsFile
+	}
+	return os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+}
+
+// resolveCloudName returns the clouds.yaml entry to load, preferring the
+// Terraform-configured value over the OpenStack-native OS_CLOUD.
+func resolveCloudName(c *Config) string {
+	if c.CloudName != "" {
+		return c.CloudName
+	}
+	return os.Getenv("OS_CLOUD")
+}
+
+// resolveWebIdentityTokenFile returns the path to the OIDC token to exchange
+// via STS AssumeRoleWithWebIdentity, preferring the Terraform-configured
+// value over AWS_WEB_IDENTITY_TOKEN_FILE.
+func resolveWebIdentityTokenFile(c *Config) string {
+	if c.WebIdentityTokenFile != "" {
+		return c.WebIdentityTokenFile
 	}
+	return os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+}
 
-	return awsCredentials.NewChainCredentials(providers), nil
+// resolveWebIdentityRoleARN returns the role to assume via web identity
+// federation, preferring the Terraform-configured value over AWS_ROLE_ARN.
+func resolveWebIdentityRoleARN(c *Config) string {
+	if c.RoleARN != "" {
+		return c.RoleARN
+	}
+	return os.Getenv("AWS_ROLE_ARN")
+}
+
+// resolveWebIdentityRoleSessionName returns the session name to use when
+// assuming a role via web identity federation, preferring the
+// Terraform-configured value, then AWS_ROLE_SESSION_NAME, then a default.
+func resolveWebIdentityRoleSessionName(c *Config) string {
+	if c.RoleSessionName != "" {
+		return c.RoleSessionName
+	}
+	if v := os.Getenv("AWS_ROLE_SESSION_NAME"); v != "" {
+		return v
+	}
+	return "terraform-provider-flexibleengine"
 }
 
 func setOptionalEndpoint(cfg *aws.Config) string {
@@ -0,0 +1,331 @@
+package flexibleengine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// fakeSTSClient lets tests observe whether AssumeRole was invoked, and
+// with which input, without talking to a real STS endpoint.
+type fakeSTSClient struct {
+	input *sts.AssumeRoleInput
+}
+
+func (f *fakeSTSClient) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	f.input = input
+	return &sts.AssumeRoleOutput{
+		Credentials: &sts.Credentials{
+			AccessKeyId:     aws.String("assumed-access-key"),
+			SecretAccessKey: aws.String("assumed-secret-key"),
+			SessionToken:    aws.String("assumed-session-token"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil
+}
+
+func TestAssumeRoleCredentials_WrapsWhenRoleARNSet(t *testing.T) {
+	fake := &fakeSTSClient{}
+	c := &Config{
+		AssumeRoleARN:         "arn:aws:iam::123456789012:role/test",
+		AssumeRoleSessionName: "terraform-flexibleengine",
+		AssumeRoleExternalID:  "external-id",
+		AssumeRolePolicy:      `{"Version":"2012-10-17"}`,
+	}
+
+	creds := assumeRoleCredentials(c, fake)
+	value, err := creds.Get()
+	if err != nil {
+		t.Fatalf("unexpected error retrieving wrapped credentials: %s", err)
+	}
+
+	if fake.input == nil {
+		t.Fatal("expected AssumeRole to be called on the injected STS client")
+	}
+	if aws.StringValue(fake.input.RoleArn) != c.AssumeRoleARN {
+		t.Errorf("got RoleArn %q, want %q", aws.StringValue(fake.input.RoleArn), c.AssumeRoleARN)
+	}
+	if aws.StringValue(fake.input.RoleSessionName) != c.AssumeRoleSessionName {
+		t.Errorf("got RoleSessionName %q, want %q", aws.StringValue(fake.input.RoleSessionName), c.AssumeRoleSessionName)
+	}
+	if aws.StringValue(fake.input.ExternalId) != c.AssumeRoleExternalID {
+		t.Errorf("got ExternalId %q, want %q", aws.StringValue(fake.input.ExternalId), c.AssumeRoleExternalID)
+	}
+	if aws.StringValue(fake.input.Policy) != c.AssumeRolePolicy {
+		t.Errorf("got Policy %q, want %q", aws.StringValue(fake.input.Policy), c.AssumeRolePolicy)
+	}
+	if value.AccessKeyID != "assumed-access-key" {
+		t.Errorf("got AccessKeyID %q, want assumed-access-key", value.AccessKeyID)
+	}
+}
+
+func TestGetAccountInfo_SkipsLookupWhenConfigured(t *testing.T) {
+	c := &Config{
+		SkipRequestingAccountID: true,
+		DomainID:                "static-domain-id",
+		AccountID:               "static-account-id",
+	}
+
+	domainID, accountID, err := GetAccountInfo(c, nil, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if domainID != c.DomainID {
+		t.Errorf("got domainID %q, want %q", domainID, c.DomainID)
+	}
+	if accountID != c.AccountID {
+		t.Errorf("got accountID %q, want %q", accountID, c.AccountID)
+	}
+}
+
+func TestGetAccountInfo_SkipsSTSAndIAMWhenCredentialsValidationSkipped(t *testing.T) {
+	c := &Config{
+		SkipCredentialsValidation: true,
+		DomainID:                  "static-domain-id",
+		AccountID:                 "static-account-id",
+	}
+
+	// Passing nil iamconn/stsconn would panic if GetAccountInfo tried to use
+	// them, proving no STS/IAM call is made when this flag is set.
+	domainID, accountID, err := GetAccountInfo(c, nil, nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if domainID != c.DomainID {
+		t.Errorf("got domainID %q, want %q", domainID, c.DomainID)
+	}
+	if accountID != c.AccountID {
+		t.Errorf("got accountID %q, want %q", accountID, c.AccountID)
+	}
+}
+
+// TestGetCredentials_CloudsYAMLOutranksEnvAndSharedFile verifies the chain
+// order: a cloud explicitly selected via cloud_name is a deliberate choice
+// of identity and must win over ambient AWS_PROFILE/AWS_* env credentials
+// and the default shared credentials file, not be silently shadowed by them.
+func TestGetCredentials_CloudsYAMLOutranksEnvAndSharedFile(t *testing.T) {
+	dir := t.TempDir()
+	cloudsYAMLPath := filepath.Join(dir, "clouds.yaml")
+	if err := ioutil.WriteFile(cloudsYAMLPath, []byte(`
+clouds:
+  testcloud:
+    auth:
+      access_key: cloud-access-key
+      secret_key: cloud-secret-key
+`), 0600); err != nil {
+		t.Fatalf("writing clouds.yaml: %s", err)
+	}
+
+	sharedCredsPath := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(sharedCredsPath, []byte(`
+[shared-profile]
+aws_access_key_id = shared-access-key
+aws_secret_access_key = shared-secret-key
+`), 0600); err != nil {
+		t.Fatalf("writing shared credentials file: %s", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("os.Getwd: %s", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("os.Chdir: %s", err)
+	}
+	defer os.Chdir(wd)
+
+	restore := setEnv(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":           "env-access-key",
+		"AWS_SECRET_ACCESS_KEY":       "env-secret-key",
+		"AWS_PROFILE":                 "shared-profile",
+		"AWS_SHARED_CREDENTIALS_FILE": sharedCredsPath,
+	})
+	defer restore()
+
+	creds, err := GetCredentials(&Config{CloudName: "testcloud", SkipMetadataApiCheck: true})
+	if err != nil {
+		t.Fatalf("GetCredentials: %s", err)
+	}
+
+	value, err := creds.Get()
+	if err != nil {
+		t.Fatalf("creds.Get(): %s", err)
+	}
+	if value.AccessKeyID != "cloud-access-key" {
+		t.Errorf("got AccessKeyID %q, want the clouds.yaml value %q, not the env or shared-file value", value.AccessKeyID, "cloud-access-key")
+	}
+}
+
+func TestResolveProfile_Precedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		awsProfile string
+		osProfile  string
+		want       string
+	}{
+		{"nothing set", "", "", "", ""},
+		{"OS_PROFILE only", "", "", "os-profile", "os-profile"},
+		{"AWS_PROFILE beats OS_PROFILE", "", "aws-profile", "os-profile", "aws-profile"},
+		{"config beats both env vars", "config-profile", "aws-profile", "os-profile", "config-profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := setEnv(t, map[string]string{
+				"AWS_PROFILE": tt.awsProfile,
+				"OS_PROFILE":  tt.osProfile,
+			})
+			defer restore()
+
+			got := resolveProfile(&Config{Profile: tt.config})
+			if got != tt.want {
+				t.Errorf("resolveProfile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSharedCredentialsFile_Precedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		awsFile string
+		want    string
+	}{
+		{"nothing set", "", "", ""},
+		{"AWS_SHARED_CREDENTIALS_FILE only", "", "/aws/credentials", "/aws/credentials"},
+		{"config beats env var", "/config/credentials", "/aws/credentials", "/config/credentials"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := setEnv(t, map[string]string{
+				"AWS_SHARED_CREDENTIALS_FILE": tt.awsFile,
+			})
+			defer restore()
+
+			got := resolveSharedCredentialsFile(&Config{SharedCredentialsFile: tt.config})
+			if got != tt.want {
+				t.Errorf("resolveSharedCredentialsFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCloudName_Precedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		osCloud string
+		want    string
+	}{
+		{"nothing set", "", "", ""},
+		{"OS_CLOUD only", "", "os-cloud", "os-cloud"},
+		{"config beats OS_CLOUD", "config-cloud", "os-cloud", "config-cloud"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := setEnv(t, map[string]string{
+				"OS_CLOUD": tt.osCloud,
+			})
+			defer restore()
+
+			got := resolveCloudName(&Config{CloudName: tt.config})
+			if got != tt.want {
+				t.Errorf("resolveCloudName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStsConfig_UsesRegionNotMetadataEndpoint covers both the assume_role
+// and web_identity_token_file STS clients, which both build their session
+// from stsConfig: it must carry the configured region (required by the SDK
+// signer for AssumeRole/AssumeRoleWithWebIdentity) and must not point at
+// the unrelated EC2 metadata endpoint set via AWS_METADATA_URL.
+func TestStsConfig_UsesRegionNotMetadataEndpoint(t *testing.T) {
+	restore := setEnv(t, map[string]string{"AWS_METADATA_URL": "http://169.254.169.254/metadata"})
+	defer restore()
+
+	cfg := stsConfig("eu-west-0")
+
+	if got := aws.StringValue(cfg.Region); got != "eu-west-0" {
+		t.Errorf("stsConfig().Region = %q, want %q", got, "eu-west-0")
+	}
+	if cfg.Endpoint != nil {
+		t.Errorf("stsConfig().Endpoint = %q, want nil (must not reuse the metadata endpoint)", aws.StringValue(cfg.Endpoint))
+	}
+}
+
+func TestResolveWebIdentityTokenFile_Precedence(t *testing.T) {
+	restore := setEnv(t, map[string]string{"AWS_WEB_IDENTITY_TOKEN_FILE": "/var/run/secrets/token"})
+	defer restore()
+
+	if got := resolveWebIdentityTokenFile(&Config{}); got != "/var/run/secrets/token" {
+		t.Errorf("resolveWebIdentityTokenFile() = %q, want env fallback", got)
+	}
+	if got := resolveWebIdentityTokenFile(&Config{WebIdentityTokenFile: "/config/token"}); got != "/config/token" {
+		t.Errorf("resolveWebIdentityTokenFile() = %q, want config value", got)
+	}
+}
+
+func TestResolveWebIdentityRoleARN_Precedence(t *testing.T) {
+	restore := setEnv(t, map[string]string{"AWS_ROLE_ARN": "arn:aws:iam::123456789012:role/env"})
+	defer restore()
+
+	if got := resolveWebIdentityRoleARN(&Config{}); got != "arn:aws:iam::123456789012:role/env" {
+		t.Errorf("resolveWebIdentityRoleARN() = %q, want env fallback", got)
+	}
+	if got := resolveWebIdentityRoleARN(&Config{RoleARN: "arn:aws:iam::123456789012:role/config"}); got != "arn:aws:iam::123456789012:role/config" {
+		t.Errorf("resolveWebIdentityRoleARN() = %q, want config value", got)
+	}
+}
+
+func TestResolveWebIdentityRoleSessionName_Precedence(t *testing.T) {
+	restore := setEnv(t, map[string]string{"AWS_ROLE_SESSION_NAME": ""})
+	defer restore()
+
+	if got := resolveWebIdentityRoleSessionName(&Config{}); got != "terraform-provider-flexibleengine" {
+		t.Errorf("resolveWebIdentityRoleSessionName() = %q, want default", got)
+	}
+	if got := resolveWebIdentityRoleSessionName(&Config{RoleSessionName: "custom-session"}); got != "custom-session" {
+		t.Errorf("resolveWebIdentityRoleSessionName() = %q, want config value", got)
+	}
+}
+
+// setEnv sets each non-empty value and unsets empty ones, returning a
+// restore func that puts the previous values back.
+func setEnv(t *testing.T, vars map[string]string) func() {
+	t.Helper()
+	prev := make(map[string]string, len(vars))
+	hadPrev := make(map[string]bool, len(vars))
+
+	for k, v := range vars {
+		if old, ok := os.LookupEnv(k); ok {
+			prev[k] = old
+			hadPrev[k] = true
+		}
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+	}
+
+	return func() {
+		for k := range vars {
+			if hadPrev[k] {
+				os.Setenv(k, prev[k])
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package flexibleengine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CloudAuth holds the credentials resolved from a named entry in a
+// clouds.yaml file, the format used throughout the OpenStack/FlexibleEngine
+// tooling ecosystem so AK/SK, project and region don't have to be spread
+// across environment variables.
+type CloudAuth struct {
+	AccessKey string
+	SecretKey string
+	ProjectID string
+	DomainID  string
+	Region    string
+}
+
+type cloudsYAML struct {
+	Clouds map[string]struct {
+		Auth struct {
+			AccessKey string `yaml:"access_key"`
+			SecretKey string `yaml:"secret_key"`
+			ProjectID string `yaml:"project_id"`
+			DomainID  string `yaml:"domain_id"`
+		} `yaml:"auth"`
+		RegionName string `yaml:"region_name"`
+	} `yaml:"clouds"`
+}
+
+// cloudsYAMLSearchPaths mirrors the lookup order used by the OpenStack CLI
+// and SDKs: current directory first, then the user's config directory,
+// then the system-wide config directory.
+func cloudsYAMLSearchPaths() []string {
+	paths := []string{"clouds.yaml"}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "openstack", "clouds.yaml"))
+	}
+	paths = append(paths, "/etc/openstack/clouds.yaml")
+	return paths
+}
+
+// loadCloudAuth reads the named cloud entry from the first clouds.yaml on
+// cloudsYAMLSearchPaths that defines it. A file that exists but lacks the
+// entry is skipped; a file that fails to parse ends the search with an error.
+func loadCloudAuth(cloudName string) (*CloudAuth, error) {
+	var lastErr error
+	for _, path := range cloudsYAMLSearchPaths() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var parsed cloudsYAML
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %s", path, err)
+		}
+
+		cloud, ok := parsed.Clouds[cloudName]
+		if !ok {
+			lastErr = fmt.Errorf("cloud %q not found in %s", cloudName, path)
+			continue
+		}
+
+		return &CloudAuth{
+			AccessKey: cloud.Auth.AccessKey,
+			SecretKey: cloud.Auth.SecretKey,
+			ProjectID: cloud.Auth.ProjectID,
+			DomainID:  cloud.Auth.DomainID,
+			Region:    cloud.RegionName,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unable to load cloud %q: %s", cloudName, lastErr)
+}
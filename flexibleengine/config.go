@@ -0,0 +1,56 @@
+package flexibleengine
+
+// Config holds the provider-level settings used to build the
+// credentials chain and the API clients derived from it.
+type Config struct {
+	AccessKey     string
+	SecretKey     string
+	SecurityToken string
+	Region        string
+
+	// AssumeRole, when AssumeRoleARN is set, causes GetCredentials to
+	// wrap the base credential chain with a temporary session obtained
+	// via STS AssumeRole, mirroring the AWS provider's behavior.
+	AssumeRoleARN         string
+	AssumeRoleExternalID  string
+	AssumeRoleSessionName string
+	AssumeRolePolicy      string
+
+	// AccountID and DomainID are used verbatim by GetAccountInfo when
+	// SkipRequestingAccountID is set, instead of deriving them from an API call.
+	AccountID string
+	DomainID  string
+
+	// SkipCredentialsValidation skips the STS/IAM calls GetAccountInfo
+	// normally makes at startup to sanity-check the resolved credentials,
+	// returning DomainID/AccountID (or empty strings, if also unset)
+	// immediately instead.
+	SkipCredentialsValidation bool
+	// SkipRequestingAccountID skips deriving the account/domain ID via the
+	// API and uses AccountID/DomainID as configured instead.
+	SkipRequestingAccountID bool
+	// SkipMetadataApiCheck disables the EC2 metadata API probe in
+	// GetCredentials, so no ec2metadata client is built and no
+	// EC2RoleProvider is added to the credentials chain.
+	SkipMetadataApiCheck bool
+
+	// Profile and SharedCredentialsFile select the named profile and file
+	// read by the SharedCredentialsProvider. Both fall back to environment
+	// variables when left empty; see resolveProfile/resolveSharedCredentialsFile.
+	Profile               string
+	SharedCredentialsFile string
+
+	// CloudName names an entry in a clouds.yaml file (the OpenStack/
+	// FlexibleEngine-native equivalent of a shared credentials profile) to
+	// pull AK/SK from. Falls back to OS_CLOUD when empty.
+	CloudName string
+
+	// WebIdentityTokenFile, RoleARN and RoleSessionName configure a web
+	// identity / OIDC federation provider (e.g. EKS IRSA or GitHub Actions
+	// OIDC), exchanging the JWT at WebIdentityTokenFile for temporary
+	// credentials via STS AssumeRoleWithWebIdentity. All three fall back to
+	// their AWS_* environment variable equivalents when empty.
+	WebIdentityTokenFile string
+	RoleARN              string
+	RoleSessionName      string
+}